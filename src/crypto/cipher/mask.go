@@ -0,0 +1,27 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher
+
+// MaskBytes xors src into dst using a repeating 4-byte key, the masking
+// primitive used by the WebSocket protocol to mask and unmask frame
+// payloads (RFC 6455, Section 5.3). The mask byte applied to src[i] is
+// key[(pos+i)%4]; pos is the offset within the key of the first byte of
+// src, which lets a payload be masked incrementally across several calls
+// by feeding back the returned position. MaskBytes returns the position
+// to pass to the next call.
+//
+// MaskBytes panics if dst is shorter than src.
+func MaskBytes(dst, src []byte, key [4]byte, pos int) int {
+	n := len(src)
+	if len(dst) < n {
+		panic("cipher: dst too short")
+	}
+	pos &= 3
+	if n == 0 {
+		return pos
+	}
+	maskBytes(dst[:n], src, key, pos)
+	return (pos + n) & 3
+}
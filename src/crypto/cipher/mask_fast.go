@@ -0,0 +1,38 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (amd64 || arm64) && !purego
+
+package cipher
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// maskBytes xors src into dst using key, rotated so key[pos] lines up with
+// src[0]. The bulk of the work is done 8 bytes at a time against a 64-bit
+// word made of two copies of the rotated 32-bit key: since the chunk size
+// (8) is a multiple of the key period (4), that word is the same for every
+// chunk and never needs to be re-rotated as the loop advances.
+func maskBytes(dst, src []byte, key [4]byte, pos int) {
+	k32 := bits.RotateLeft32(binary.LittleEndian.Uint32(key[:]), -8*pos)
+	word := uint64(k32) | uint64(k32)<<32
+
+	for len(src) >= 8 {
+		v := binary.LittleEndian.Uint64(src)
+		binary.LittleEndian.PutUint64(dst, v^word)
+		src = src[8:]
+		dst = dst[8:]
+	}
+	if len(src) >= 4 {
+		v := binary.LittleEndian.Uint32(src)
+		binary.LittleEndian.PutUint32(dst, v^k32)
+		src = src[4:]
+		dst = dst[4:]
+	}
+	for i, b := range src {
+		dst[i] = b ^ byte(k32>>(8*uint(i)))
+	}
+}
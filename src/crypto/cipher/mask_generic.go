@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !((amd64 || arm64) && !purego)
+
+package cipher
+
+func maskBytes(dst, src []byte, key [4]byte, pos int) {
+	for i, b := range src {
+		dst[i] = b ^ key[(pos+i)&3]
+	}
+}
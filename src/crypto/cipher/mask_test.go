@@ -0,0 +1,70 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher_test
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func maskBytesReference(dst, src []byte, key [4]byte, pos int) int {
+	for i, b := range src {
+		dst[i] = b ^ key[(pos+i)%4]
+	}
+	return (pos + len(src)) % 4
+}
+
+func TestMaskBytes(t *testing.T) {
+	var key [4]byte
+	if _, err := io.ReadFull(rand.Reader, key[:]); err != nil {
+		t.Fatal(err)
+	}
+
+	for n := 0; n <= 300; n++ {
+		for pos := 0; pos < 4; pos++ {
+			for alignSrc := 0; alignSrc < 2; alignSrc++ {
+				for alignDst := 0; alignDst < 2; alignDst++ {
+					src := make([]byte, n+alignSrc)[alignSrc:]
+					if _, err := io.ReadFull(rand.Reader, src); err != nil {
+						t.Fatal(err)
+					}
+					got := make([]byte, n+alignDst)[alignDst:]
+					want := make([]byte, n+alignDst)[alignDst:]
+
+					gotPos := cipher.MaskBytes(got, src, key, pos)
+					wantPos := maskBytesReference(want, src, key, pos)
+
+					if gotPos != wantPos {
+						t.Fatalf("n=%d pos=%d: got pos %d, want %d", n, pos, gotPos, wantPos)
+					}
+					if !bytes.Equal(got, want) {
+						t.Fatalf("n=%d pos=%d alignSrc=%d alignDst=%d:\n got  %#v\n want %#v", n, pos, alignSrc, alignDst, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkMaskBytes(b *testing.B) {
+	var key [4]byte
+	dst := make([]byte, 1<<15)
+	src := make([]byte, 1<<15)
+	sizes := []int64{1 << 3, 1 << 7, 1 << 11, 1 << 15}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			s := src[:size]
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				cipher.MaskBytes(dst, s, key, 0)
+			}
+		})
+	}
+}
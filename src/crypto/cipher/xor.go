@@ -0,0 +1,21 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher
+
+// XorBytes sets dst[i] = a[i] ^ b[i] for all i < n = min(len(a), len(b)),
+// returning n, the number of bytes written to dst.
+// If dst does not have length at least n, XorBytes panics without
+// writing anything to dst.
+func XorBytes(dst, a, b []byte) int {
+	n := min(len(a), len(b))
+	if n == 0 {
+		return 0
+	}
+	if n > len(dst) {
+		panic("cipher: dst too short")
+	}
+	xorBytes(&dst[0], &a[0], &b[0], n)
+	return n
+}
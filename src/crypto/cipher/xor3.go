@@ -0,0 +1,25 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher
+
+// XorBytesTo3 sets dst[i] = a[i] ^ b[i] ^ c[i] for all
+// i < n = min(len(a), len(b), len(c)), returning n, the number of bytes
+// written to dst. It fuses what would otherwise be two calls to XorBytes
+// into a single pass over memory, which several AEAD constructions rely
+// on to apply a keystream to plaintext while simultaneously accumulating
+// into a GHASH/POLYVAL buffer.
+// If dst does not have length at least n, XorBytesTo3 panics without
+// writing anything to dst.
+func XorBytesTo3(dst, a, b, c []byte) int {
+	n := min(len(a), len(b), len(c))
+	if n == 0 {
+		return 0
+	}
+	if n > len(dst) {
+		panic("cipher: dst too short")
+	}
+	xorBytesTo3(&dst[0], &a[0], &b[0], &c[0], n)
+	return n
+}
@@ -0,0 +1,10 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && !purego
+
+package cipher
+
+//go:noescape
+func xorBytesTo3(dst, a, b, c *byte, n int)
@@ -0,0 +1,68 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (!amd64 && !arm64) || purego
+
+package cipher
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// xorBytesTo3 mirrors the unrolled 128/64/32/16/8 ladder in xor_generic.go,
+// fused across three source streams.
+func xorBytesTo3(dstp, ap, bp, cp *byte, n int) {
+	dst := unsafe.Slice(dstp, n)
+	a := unsafe.Slice(ap, n)
+	b := unsafe.Slice(bp, n)
+	c := unsafe.Slice(cp, n)
+
+	for len(a) >= 128 {
+		xorWords16To3(dst, a, b, c)
+		dst, a, b, c = dst[128:], a[128:], b[128:], c[128:]
+	}
+	for len(a) >= 64 {
+		xorWords8To3(dst, a, b, c)
+		dst, a, b, c = dst[64:], a[64:], b[64:], c[64:]
+	}
+	for len(a) >= 32 {
+		xorWords4To3(dst, a, b, c)
+		dst, a, b, c = dst[32:], a[32:], b[32:], c[32:]
+	}
+	for len(a) >= 16 {
+		xorWords2To3(dst, a, b, c)
+		dst, a, b, c = dst[16:], a[16:], b[16:], c[16:]
+	}
+	for len(a) >= 8 {
+		v := binary.LittleEndian.Uint64(a) ^ binary.LittleEndian.Uint64(b) ^ binary.LittleEndian.Uint64(c)
+		binary.LittleEndian.PutUint64(dst, v)
+		dst, a, b, c = dst[8:], a[8:], b[8:], c[8:]
+	}
+	for i := range a {
+		dst[i] = a[i] ^ b[i] ^ c[i]
+	}
+}
+
+func xorWords16To3(dst, a, b, c []byte) {
+	xorWords8To3(dst, a, b, c)
+	xorWords8To3(dst[64:], a[64:], b[64:], c[64:])
+}
+
+func xorWords8To3(dst, a, b, c []byte) {
+	xorWords4To3(dst, a, b, c)
+	xorWords4To3(dst[32:], a[32:], b[32:], c[32:])
+}
+
+func xorWords4To3(dst, a, b, c []byte) {
+	xorWords2To3(dst, a, b, c)
+	xorWords2To3(dst[16:], a[16:], b[16:], c[16:])
+}
+
+func xorWords2To3(dst, a, b, c []byte) {
+	v := binary.LittleEndian.Uint64(a) ^ binary.LittleEndian.Uint64(b) ^ binary.LittleEndian.Uint64(c)
+	binary.LittleEndian.PutUint64(dst, v)
+	v = binary.LittleEndian.Uint64(a[8:]) ^ binary.LittleEndian.Uint64(b[8:]) ^ binary.LittleEndian.Uint64(c[8:])
+	binary.LittleEndian.PutUint64(dst[8:], v)
+}
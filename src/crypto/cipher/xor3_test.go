@@ -0,0 +1,89 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher_test
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestXorBytesTo3(t *testing.T) {
+	for n := 0; n <= 300; n++ {
+		a := make([]byte, n)
+		b := make([]byte, n)
+		c := make([]byte, n)
+		for _, s := range [][]byte{a, b, c} {
+			if _, err := io.ReadFull(rand.Reader, s); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got := make([]byte, n)
+		if gotN := cipher.XorBytesTo3(got, a, b, c); gotN != n {
+			t.Fatalf("n=%d: XorBytesTo3 returned %d", n, gotN)
+		}
+
+		want := make([]byte, n)
+		for i := range want {
+			want[i] = a[i] ^ b[i] ^ c[i]
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("n=%d:\n got  %#v\n want %#v", n, got, want)
+		}
+	}
+}
+
+func TestXorBytesTo3ShortestWins(t *testing.T) {
+	a := make([]byte, 10)
+	b := make([]byte, 5)
+	c := make([]byte, 7)
+	for _, s := range [][]byte{a, b, c} {
+		if _, err := io.ReadFull(rand.Reader, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dst := make([]byte, 10)
+	n := cipher.XorBytesTo3(dst, a, b, c)
+	if n != 5 {
+		t.Fatalf("got n=%d, want 5", n)
+	}
+	for i := 0; i < n; i++ {
+		if want := a[i] ^ b[i] ^ c[i]; dst[i] != want {
+			t.Fatalf("dst[%d] = %#x, want %#x", i, dst[i], want)
+		}
+	}
+}
+
+func BenchmarkXorBytesTo3(b *testing.B) {
+	dst := make([]byte, 1<<16)
+	tmp := make([]byte, 1<<16)
+	a := make([]byte, 1<<16)
+	k := make([]byte, 1<<16)
+	g := make([]byte, 1<<16)
+	sizes := []int64{1 << 6, 1 << 8, 1 << 10, 1 << 12, 1 << 14, 1 << 16}
+
+	for _, size := range sizes {
+		sa, sk, sg := a[:size], k[:size], g[:size]
+
+		b.Run(fmt.Sprintf("TwoPass/%dB", size), func(b *testing.B) {
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				cipher.XorBytes(tmp[:size], sa, sk)
+				cipher.XorBytes(dst[:size], tmp[:size], sg)
+			}
+		})
+		b.Run(fmt.Sprintf("Fused/%dB", size), func(b *testing.B) {
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				cipher.XorBytesTo3(dst[:size], sa, sk, sg)
+			}
+		})
+	}
+}
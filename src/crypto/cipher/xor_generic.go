@@ -0,0 +1,75 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (!amd64 && !arm64) || purego
+
+package cipher
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// xorBytes is the portable fallback used on architectures without a
+// hand-written xorBytes assembly implementation, such as riscv64, loong64
+// and wasm. It is unrolled in descending powers of two down to a single
+// byte so that the common case, src and dst both a multiple of 8 bytes,
+// never falls through to the byte-at-a-time tail.
+func xorBytes(dstp, ap, bp *byte, n int) {
+	dst := unsafe.Slice(dstp, n)
+	a := unsafe.Slice(ap, n)
+	b := unsafe.Slice(bp, n)
+
+	for len(a) >= 128 {
+		xorWords16(dst, a, b)
+		dst, a, b = dst[128:], a[128:], b[128:]
+	}
+	for len(a) >= 64 {
+		xorWords8(dst, a, b)
+		dst, a, b = dst[64:], a[64:], b[64:]
+	}
+	for len(a) >= 32 {
+		xorWords4(dst, a, b)
+		dst, a, b = dst[32:], a[32:], b[32:]
+	}
+	for len(a) >= 16 {
+		xorWords2(dst, a, b)
+		dst, a, b = dst[16:], a[16:], b[16:]
+	}
+	for len(a) >= 8 {
+		binary.LittleEndian.PutUint64(dst, binary.LittleEndian.Uint64(a)^binary.LittleEndian.Uint64(b))
+		dst, a, b = dst[8:], a[8:], b[8:]
+	}
+	for len(a) >= 4 {
+		binary.LittleEndian.PutUint32(dst, binary.LittleEndian.Uint32(a)^binary.LittleEndian.Uint32(b))
+		dst, a, b = dst[4:], a[4:], b[4:]
+	}
+	for i := range a {
+		dst[i] = a[i] ^ b[i]
+	}
+}
+
+// xorWords16 xors 16 little-endian uint64 words (128 bytes).
+func xorWords16(dst, a, b []byte) {
+	xorWords8(dst, a, b)
+	xorWords8(dst[64:], a[64:], b[64:])
+}
+
+// xorWords8 xors 8 little-endian uint64 words (64 bytes).
+func xorWords8(dst, a, b []byte) {
+	xorWords4(dst, a, b)
+	xorWords4(dst[32:], a[32:], b[32:])
+}
+
+// xorWords4 xors 4 little-endian uint64 words (32 bytes).
+func xorWords4(dst, a, b []byte) {
+	xorWords2(dst, a, b)
+	xorWords2(dst[16:], a[16:], b[16:])
+}
+
+// xorWords2 xors 2 little-endian uint64 words (16 bytes).
+func xorWords2(dst, a, b []byte) {
+	binary.LittleEndian.PutUint64(dst, binary.LittleEndian.Uint64(a)^binary.LittleEndian.Uint64(b))
+	binary.LittleEndian.PutUint64(dst[8:], binary.LittleEndian.Uint64(a[8:])^binary.LittleEndian.Uint64(b[8:]))
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher
+
+import (
+	"io"
+	"sync"
+)
+
+const xorBufferSize = 32 * 1024
+
+var xorBufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, xorBufferSize)
+		return &b
+	},
+}
+
+// xorKeyStream xors src into dst against key, treated as a repeating
+// keystream starting pos bytes into key, and returns the position to use
+// for the next call. len(key) must be greater than zero.
+func xorKeyStream(dst, src, key []byte, pos int) int {
+	for len(src) > 0 {
+		n := XorBytes(dst, src, key[pos:])
+		dst = dst[n:]
+		src = src[n:]
+		pos += n
+		if pos == len(key) {
+			pos = 0
+		}
+	}
+	return pos
+}
+
+// xorWriter XORs everything written to it against key before writing it to
+// w, treating key as a keystream that repeats once exhausted.
+type xorWriter struct {
+	w   io.Writer
+	key []byte
+	pos int
+}
+
+// NewXORWriter returns a Writer that XORs everything written to it against
+// key before writing it to dst. If more bytes are written than len(key),
+// key is repeated as needed; position within key is tracked across calls to Write, so a
+// payload may be written incrementally in arbitrary chunk sizes. It panics
+// if key is empty.
+func NewXORWriter(dst io.Writer, key []byte) io.Writer {
+	if len(key) == 0 {
+		panic("cipher: NewXORWriter: key is empty")
+	}
+	return &xorWriter{w: dst, key: key}
+}
+
+func (x *xorWriter) Write(p []byte) (n int, err error) {
+	bufp := xorBufferPool.Get().(*[]byte)
+	buf := *bufp
+	defer xorBufferPool.Put(bufp)
+
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > len(buf) {
+			chunk = chunk[:len(buf)]
+		}
+		x.pos = xorKeyStream(buf, chunk, x.key, x.pos)
+
+		nw, err := x.w.Write(buf[:len(chunk)])
+		n += nw
+		if err != nil {
+			return n, err
+		}
+		if nw < len(chunk) {
+			return n, io.ErrShortWrite
+		}
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+// xorReader XORs everything read from r against key, treating key as a
+// keystream that repeats once exhausted.
+type xorReader struct {
+	r   io.Reader
+	key []byte
+	pos int
+}
+
+// NewXORReader returns a Reader that reads from src and XORs the bytes read
+// against key before returning them. If more bytes are read than len(key),
+// key is repeated as needed; position within key is tracked across calls
+// to Read. It panics if key is empty.
+func NewXORReader(src io.Reader, key []byte) io.Reader {
+	if len(key) == 0 {
+		panic("cipher: NewXORReader: key is empty")
+	}
+	return &xorReader{r: src, key: key}
+}
+
+func (x *xorReader) Read(p []byte) (n int, err error) {
+	n, err = x.r.Read(p)
+	if n > 0 {
+		x.pos = xorKeyStream(p[:n], p[:n], x.key, x.pos)
+	}
+	return n, err
+}
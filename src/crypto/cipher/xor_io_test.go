@@ -0,0 +1,130 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher_test
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func xorReference(data, key []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ key[i%len(key)]
+	}
+	return out
+}
+
+func TestXORWriter(t *testing.T) {
+	key := make([]byte, 7)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []int{0, 1, 6, 7, 8, 100, 1 << 15} {
+		data := make([]byte, n)
+		if _, err := io.ReadFull(rand.Reader, data); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		w := cipher.NewXORWriter(&buf, key)
+		// Write in irregular chunk sizes to exercise pos tracking across calls.
+		for _, chunk := range splitIrregular(data, 3) {
+			if _, err := w.Write(chunk); err != nil {
+				t.Fatalf("n=%d: Write: %v", n, err)
+			}
+		}
+
+		want := xorReference(data, key)
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("n=%d: got %#v, want %#v", n, buf.Bytes(), want)
+		}
+	}
+}
+
+func TestXORReader(t *testing.T) {
+	key := make([]byte, 7)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []int{0, 1, 6, 7, 8, 100, 1 << 15} {
+		data := make([]byte, n)
+		if _, err := io.ReadFull(rand.Reader, data); err != nil {
+			t.Fatal(err)
+		}
+
+		r := cipher.NewXORReader(bytes.NewReader(data), key)
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("n=%d: ReadAll: %v", n, err)
+		}
+
+		want := xorReference(data, key)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("n=%d: got %#v, want %#v", n, got, want)
+		}
+	}
+}
+
+// splitIrregular splits data into chunks of sizes 1, 2, ..., max, 1, 2, ...
+// to exercise keystream continuation across arbitrary write boundaries.
+func splitIrregular(data []byte, max int) [][]byte {
+	var chunks [][]byte
+	size := 1
+	for len(data) > 0 {
+		n := size
+		if len(data) < n {
+			n = len(data)
+		}
+		if max < n {
+			n = max
+		}
+		chunks = append(chunks, data[:n])
+		data = data[n:]
+		size++
+		if size > max {
+			size = 1
+		}
+	}
+	return chunks
+}
+
+func BenchmarkXORWriter(b *testing.B) {
+	key := make([]byte, 32)
+	sizes := []int64{1 << 3, 1 << 7, 1 << 11, 1 << 15}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			data := make([]byte, size)
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				w := cipher.NewXORWriter(io.Discard, key)
+				w.Write(data)
+			}
+		})
+	}
+}
+
+func BenchmarkXORReader(b *testing.B) {
+	key := make([]byte, 32)
+	sizes := []int64{1 << 3, 1 << 7, 1 << 11, 1 << 15}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			data := make([]byte, size)
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				r := cipher.NewXORReader(bytes.NewReader(data), key)
+				io.ReadAll(r)
+			}
+		})
+	}
+}